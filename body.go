@@ -0,0 +1,168 @@
+package collider
+
+import "math"
+
+// CollisionCategory is a bitmask describing what a Body is
+type CollisionCategory uint32
+
+// CollisionMask is a bitmask describing what CollisionCategory a Body can
+// collide with
+type CollisionMask uint32
+
+// CategoryAll matches every CollisionCategory, the default mask for a Body
+// that doesn't need to filter anything out
+const CategoryAll CollisionMask = 0xFFFFFFFF
+
+// Body layers mass, velocity and material properties on top of a Shape so
+// SpatialHash.Step can integrate motion and resolve contacts with an
+// impulse-based response, instead of callers reimplementing physics on top
+// of SeparatingVector themselves
+type Body struct {
+	Shape Shape
+
+	Velocity *Vector
+	// InverseMass is 1/mass; 0 makes the body static (infinitely heavy and
+	// unaffected by impulses or integration)
+	InverseMass float64
+	Restitution float64
+	Friction    float64
+
+	// Category is what this Body is, Mask is what categories of Body it
+	// collides with. Two bodies only collide when each one's Mask accepts
+	// the other's Category
+	Category CollisionCategory
+	Mask     CollisionMask
+}
+
+// NewBody creates a Body for shape and registers it with the hash shape is
+// already in. mass of 0 creates a static body (InverseMass 0)
+func (s *SpatialHash) NewBody(shape Shape, mass, restitution, friction float64, category CollisionCategory, mask CollisionMask) *Body {
+	inverseMass := 0.0
+	if mass > 0 {
+		inverseMass = 1 / mass
+	}
+
+	body := &Body{
+		Shape:       shape,
+		Velocity:    &Vector{0, 0},
+		InverseMass: inverseMass,
+		Restitution: restitution,
+		Friction:    friction,
+		Category:    category,
+		Mask:        mask,
+	}
+	s.Bodies[shape] = body
+	return body
+}
+
+// RemoveBody unregisters shape's Body, leaving the shape itself in the hash
+func (s *SpatialHash) RemoveBody(shape Shape) {
+	delete(s.Bodies, shape)
+}
+
+// canCollide reports whether a and b are allowed to collide under their
+// Body category/mask filters. A shape with no registered Body always
+// collides, so static level geometry doesn't need one
+func (s *SpatialHash) canCollide(a, b Shape) bool {
+	bodyA, ok := s.Bodies[a]
+	if !ok {
+		return true
+	}
+	bodyB, ok := s.Bodies[b]
+	if !ok {
+		return true
+	}
+	return uint32(bodyA.Category)&uint32(bodyB.Mask) != 0 &&
+		uint32(bodyB.Category)&uint32(bodyA.Mask) != 0
+}
+
+// Step integrates every Body's velocity, runs CheckCollisions for every
+// shape in the world, fires any registered CollisionHandlers, and resolves
+// contacts between bodies with impulse-based response (skipping pairs
+// whose handler's PreSolve returned false this step)
+func (s *SpatialHash) Step(dt float64) {
+	for shape, body := range s.Bodies {
+		if body.InverseMass == 0 {
+			continue
+		}
+		shape.Move(body.Velocity.X*dt, body.Velocity.Y*dt)
+	}
+
+	processed := make(map[pairKey]CollisionData)
+	for shape := range s.Backref {
+		for _, col := range s.CheckCollisions(shape) {
+			key := newPairKey(shape, col.Other)
+			if _, ok := processed[key]; ok {
+				continue
+			}
+			processed[key] = col
+		}
+	}
+
+	skipResolve := s.updateArbiters(processed)
+
+	for key, col := range processed {
+		if skipResolve[key] {
+			continue
+		}
+		bodyA, ok := s.Bodies[key.A]
+		if !ok {
+			continue
+		}
+		bodyB, ok := s.Bodies[key.B]
+		if !ok {
+			continue
+		}
+		sep := col.SeparatingVector
+		if key.A == col.Other {
+			// col was recorded from key.B's perspective; its vector
+			// separates key.B from key.A, so flip it to match key.A/key.B
+			sep = sep.Mult(-1)
+		}
+		resolveContact(key.A, bodyA, key.B, bodyB, sep)
+	}
+}
+
+// resolveContact applies an impulse-based response to a contact between
+// shapeA/bodyA and shapeB/bodyB. sep is the vector that would move shapeA
+// clear of shapeB (as returned by CheckCollisions), i.e. it points away
+// from B along the contact normal.
+func resolveContact(shapeA Shape, bodyA *Body, shapeB Shape, bodyB *Body, sep *Vector) {
+	totalInverseMass := bodyA.InverseMass + bodyB.InverseMass
+	if totalInverseMass == 0 {
+		return // both static, nothing to resolve
+	}
+
+	normal := sep.Normalize()
+	relativeVelocity := bodyA.Velocity.Sub(bodyB.Velocity)
+	velocityAlongNormal := relativeVelocity.Dot(normal)
+	if velocityAlongNormal > 0 {
+		// already separating
+		return
+	}
+
+	restitution := (bodyA.Restitution + bodyB.Restitution) / 2
+	j := -(1 + restitution) * velocityAlongNormal / totalInverseMass
+	impulse := normal.Mult(j)
+	bodyA.Velocity = bodyA.Velocity.Add(impulse.Mult(bodyA.InverseMass))
+	bodyB.Velocity = bodyB.Velocity.Sub(impulse.Mult(bodyB.InverseMass))
+
+	// Coulomb friction along the tangent, clamped by mu*|j|
+	tangent := &Vector{-normal.Y, normal.X}
+	velocityAlongTangent := relativeVelocity.Dot(tangent)
+	jt := -velocityAlongTangent / totalInverseMass
+	mu := math.Sqrt(bodyA.Friction * bodyB.Friction)
+	maxFriction := mu * math.Abs(j)
+	if jt > maxFriction {
+		jt = maxFriction
+	} else if jt < -maxFriction {
+		jt = -maxFriction
+	}
+	frictionImpulse := tangent.Mult(jt)
+	bodyA.Velocity = bodyA.Velocity.Add(frictionImpulse.Mult(bodyA.InverseMass))
+	bodyB.Velocity = bodyB.Velocity.Sub(frictionImpulse.Mult(bodyB.InverseMass))
+
+	// positional correction, split by inverse mass so heavier bodies move less
+	shapeA.Move(sep.X*bodyA.InverseMass/totalInverseMass, sep.Y*bodyA.InverseMass/totalInverseMass)
+	shapeB.Move(-sep.X*bodyB.InverseMass/totalInverseMass, -sep.Y*bodyB.InverseMass/totalInverseMass)
+}
@@ -0,0 +1,87 @@
+package collider
+
+import "testing"
+
+func TestResolveContactSeparatesOverlappingBodies(t *testing.T) {
+	hash := NewSpatialHash(16)
+	a := hash.NewCircleShape(-5, 0, 10)
+	b := hash.NewCircleShape(5, 0, 10)
+
+	bodyA := hash.NewBody(a, 1, 1, 0, 1, CategoryAll)
+	bodyB := hash.NewBody(b, 1, 1, 0, 1, CategoryAll)
+	bodyA.Velocity = &Vector{5, 0}
+	bodyB.Velocity = &Vector{-5, 0}
+
+	sep := collisionCircCirc(a, b)
+	if sep.Length() == 0 {
+		t.Fatalf("expected the circles to overlap")
+	}
+
+	resolveContact(a, bodyA, b, bodyB, sep)
+
+	if bodyA.Velocity.X >= 5 {
+		t.Fatalf("expected the impulse to slow bodyA's approach, got velocity %v", bodyA.Velocity)
+	}
+	if bodyB.Velocity.X <= -5 {
+		t.Fatalf("expected the impulse to slow bodyB's approach, got velocity %v", bodyB.Velocity)
+	}
+}
+
+func TestResolveContactStaticBodyDoesNotMove(t *testing.T) {
+	hash := NewSpatialHash(16)
+	a := hash.NewCircleShape(-5, 0, 10)
+	b := hash.NewCircleShape(5, 0, 10)
+
+	bodyA := hash.NewBody(a, 1, 0, 0, 1, CategoryAll)
+	bodyB := hash.NewBody(b, 0, 0, 0, 1, CategoryAll) // static
+	bodyA.Velocity = &Vector{5, 0}
+
+	sep := collisionCircCirc(a, b)
+	resolveContact(a, bodyA, b, bodyB, sep)
+
+	if b.Pos.X != 5 || b.Pos.Y != 0 {
+		t.Fatalf("expected the static body to stay put, got %v", b.Pos)
+	}
+	if bodyB.Velocity.X != 0 || bodyB.Velocity.Y != 0 {
+		t.Fatalf("expected the static body's velocity to stay zero, got %v", bodyB.Velocity)
+	}
+}
+
+func TestResolveContactAlreadySeparatingIsNoOp(t *testing.T) {
+	hash := NewSpatialHash(16)
+	a := hash.NewCircleShape(-5, 0, 10)
+	b := hash.NewCircleShape(5, 0, 10)
+
+	bodyA := hash.NewBody(a, 1, 1, 0, 1, CategoryAll)
+	bodyB := hash.NewBody(b, 1, 1, 0, 1, CategoryAll)
+	bodyA.Velocity = &Vector{-5, 0}
+	bodyB.Velocity = &Vector{5, 0}
+
+	sep := collisionCircCirc(a, b)
+	resolveContact(a, bodyA, b, bodyB, sep)
+
+	if bodyA.Velocity.X != -5 || bodyB.Velocity.X != 5 {
+		t.Fatalf("expected velocities already separating to be left alone, got %v / %v", bodyA.Velocity, bodyB.Velocity)
+	}
+}
+
+func TestStepSkipsResolutionWhenMaskExcludesPair(t *testing.T) {
+	const (
+		categoryBullet CollisionCategory = 1 << iota
+	)
+
+	hash := NewSpatialHash(16)
+	a := hash.NewCircleShape(-5, 0, 10)
+	b := hash.NewCircleShape(5, 0, 10)
+
+	bodyA := hash.NewBody(a, 1, 1, 0, categoryBullet, CollisionMask(0))
+	bodyB := hash.NewBody(b, 1, 1, 0, categoryBullet, CollisionMask(0))
+	bodyA.Velocity = &Vector{5, 0}
+	bodyB.Velocity = &Vector{-5, 0}
+
+	hash.Step(1.0 / 60.0)
+
+	if bodyA.Velocity.X != 5 || bodyB.Velocity.X != -5 {
+		t.Fatalf("expected Step to leave velocities untouched when the masks exclude the pair, got %v / %v", bodyA.Velocity, bodyB.Velocity)
+	}
+}
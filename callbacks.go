@@ -0,0 +1,115 @@
+package collider
+
+import "reflect"
+
+// CollisionHandler receives lifecycle events for a pair of shapes that are
+// touching. Begin fires the first step they overlap, PreSolve fires every
+// step they're still overlapping (returning false skips this step's
+// impulse resolution entirely, which is how one-way platforms and sensors
+// are built), and Separate fires once they stop overlapping
+type CollisionHandler interface {
+	Begin(data CollisionData)
+	PreSolve(data CollisionData) bool
+	Separate(data CollisionData)
+}
+
+// pairKey canonically identifies an unordered pair of shapes so (a,b) and
+// (b,a) land on the same arbiter/handler
+type pairKey struct {
+	A, B Shape
+}
+
+func shapeAddr(shape Shape) uintptr {
+	return reflect.ValueOf(shape).Pointer()
+}
+
+func newPairKey(a, b Shape) pairKey {
+	if shapeAddr(a) <= shapeAddr(b) {
+		return pairKey{a, b}
+	}
+	return pairKey{b, a}
+}
+
+// arbiter tracks a single colliding pair's handler across steps
+type arbiter struct {
+	handler CollisionHandler
+	data    CollisionData
+	touched bool
+}
+
+// OnCollision registers handler for collisions between a and b. If b is
+// nil, handler instead applies to every collision involving a (a per-shape
+// handler), with pair-specific handlers taking priority when both exist.
+// Handlers only fire while the world is advanced with SpatialHash.Step
+func (s *SpatialHash) OnCollision(a, b Shape, handler CollisionHandler) {
+	if b == nil {
+		s.shapeHandlers[a] = handler
+		return
+	}
+	s.pairHandlers[newPairKey(a, b)] = handler
+}
+
+// RemoveCollision unregisters a handler previously added with OnCollision.
+// Pass the same a,b (or a,nil) used to register it
+func (s *SpatialHash) RemoveCollision(a, b Shape) {
+	if b == nil {
+		delete(s.shapeHandlers, a)
+		return
+	}
+	delete(s.pairHandlers, newPairKey(a, b))
+}
+
+// handlerFor returns the handler that applies to a collision between a and
+// b, preferring a pair-specific handler over either shape's per-shape one
+func (s *SpatialHash) handlerFor(a, b Shape) CollisionHandler {
+	if handler, ok := s.pairHandlers[newPairKey(a, b)]; ok {
+		return handler
+	}
+	if handler, ok := s.shapeHandlers[a]; ok {
+		return handler
+	}
+	if handler, ok := s.shapeHandlers[b]; ok {
+		return handler
+	}
+	return nil
+}
+
+// updateArbiters fires Begin/PreSolve for every currently-touching pair
+// that has a handler registered, and Separate for pairs whose arbiter
+// wasn't touched this step (i.e. they stopped overlapping). It returns the
+// set of pairs that should skip impulse resolution this step because a
+// handler's PreSolve returned false
+func (s *SpatialHash) updateArbiters(processed map[pairKey]CollisionData) map[pairKey]bool {
+	skipResolve := make(map[pairKey]bool)
+
+	for key, col := range processed {
+		handler := s.handlerFor(key.A, key.B)
+		if handler == nil {
+			continue
+		}
+
+		arb, existed := s.arbiters[key]
+		if !existed {
+			arb = &arbiter{handler: handler}
+			s.arbiters[key] = arb
+			handler.Begin(col)
+		}
+		arb.touched = true
+		arb.data = col
+
+		if !handler.PreSolve(col) {
+			skipResolve[key] = true
+		}
+	}
+
+	for key, arb := range s.arbiters {
+		if !arb.touched {
+			arb.handler.Separate(arb.data)
+			delete(s.arbiters, key)
+			continue
+		}
+		arb.touched = false
+	}
+
+	return skipResolve
+}
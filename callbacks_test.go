@@ -0,0 +1,95 @@
+package collider
+
+import "testing"
+
+type recordingHandler struct {
+	begins, preSolves, separates int
+	preSolveResult               bool
+}
+
+func (h *recordingHandler) Begin(data CollisionData) { h.begins++ }
+func (h *recordingHandler) PreSolve(data CollisionData) bool {
+	h.preSolves++
+	return h.preSolveResult
+}
+func (h *recordingHandler) Separate(data CollisionData) { h.separates++ }
+
+func TestUpdateArbitersFiresBeginOnce(t *testing.T) {
+	hash := NewSpatialHash(16)
+	a := hash.NewCircleShape(0, 0, 10)
+	b := hash.NewCircleShape(5, 0, 10)
+
+	handler := &recordingHandler{preSolveResult: true}
+	hash.OnCollision(a, b, handler)
+
+	key := newPairKey(a, b)
+	processed := map[pairKey]CollisionData{
+		key: {Other: b, SeparatingVector: &Vector{1, 0}},
+	}
+
+	hash.updateArbiters(processed)
+	hash.updateArbiters(processed)
+
+	if handler.begins != 1 {
+		t.Fatalf("expected Begin to fire once across repeated touching steps, got %d", handler.begins)
+	}
+	if handler.preSolves != 2 {
+		t.Fatalf("expected PreSolve to fire every step, got %d", handler.preSolves)
+	}
+	if handler.separates != 0 {
+		t.Fatalf("expected Separate not to fire while still touching, got %d", handler.separates)
+	}
+}
+
+func TestUpdateArbitersFiresSeparateWhenUntouched(t *testing.T) {
+	hash := NewSpatialHash(16)
+	a := hash.NewCircleShape(0, 0, 10)
+	b := hash.NewCircleShape(5, 0, 10)
+
+	handler := &recordingHandler{preSolveResult: true}
+	hash.OnCollision(a, b, handler)
+
+	key := newPairKey(a, b)
+	processed := map[pairKey]CollisionData{
+		key: {Other: b, SeparatingVector: &Vector{1, 0}},
+	}
+	hash.updateArbiters(processed)
+
+	hash.updateArbiters(map[pairKey]CollisionData{})
+
+	if handler.separates != 1 {
+		t.Fatalf("expected Separate to fire once the pair stopped touching, got %d", handler.separates)
+	}
+	if _, ok := hash.arbiters[key]; ok {
+		t.Fatalf("expected the arbiter to be dropped after Separate fired")
+	}
+}
+
+func TestUpdateArbitersSkipsResolutionWhenPreSolveRefuses(t *testing.T) {
+	hash := NewSpatialHash(16)
+	a := hash.NewCircleShape(0, 0, 10)
+	b := hash.NewCircleShape(5, 0, 10)
+
+	handler := &recordingHandler{preSolveResult: false}
+	hash.OnCollision(a, b, handler)
+
+	key := newPairKey(a, b)
+	processed := map[pairKey]CollisionData{
+		key: {Other: b, SeparatingVector: &Vector{1, 0}},
+	}
+
+	skip := hash.updateArbiters(processed)
+	if !skip[key] {
+		t.Fatalf("expected the pair to be marked for skipped resolution when PreSolve returns false")
+	}
+}
+
+func TestPairKeyIsOrderIndependent(t *testing.T) {
+	hash := NewSpatialHash(16)
+	a := hash.NewCircleShape(0, 0, 10)
+	b := hash.NewCircleShape(5, 0, 10)
+
+	if newPairKey(a, b) != newPairKey(b, a) {
+		t.Fatalf("expected newPairKey(a, b) == newPairKey(b, a)")
+	}
+}
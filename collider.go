@@ -24,6 +24,11 @@ type Shape interface {
 	MoveTo(x, y float64)    // move to position
 	SetHash(s *SpatialHash) // sets ref to hash
 	GetHash() *SpatialHash  // gets	 ref to hash
+
+	// MoveSwept moves the shape by x,y but stops at the earliest time of
+	// impact against anything in its path, returning that time (in [0,1],
+	// where 1 means the move completed) and the collisions found there
+	MoveSwept(x, y float64) (t float64, hits []CollisionData)
 }
 
 // CircleShape shape
@@ -55,6 +60,10 @@ type CellCoord struct {
 	X, Y int
 }
 
+// Backref is the small set of cells a shape currently occupies, used to
+// find its neighbours and to diff against on Update
+type Backref map[CellCoord]bool
+
 // SpatialHash contains cells
 type SpatialHash struct {
 	// Size of the grid/cell/partition
@@ -62,21 +71,37 @@ type SpatialHash struct {
 	// Store shapes in a cell depending on their bounds
 	Hash map[CellCoord]*Cell
 	// Backref for shapes to find its containing cells
-	Backref map[Shape][]*Cell
+	Backref map[Shape]Backref
+	// Bodies holds the physics Body registered for a shape, if any
+	Bodies map[Shape]*Body
+
+	// arbiters tracks the currently-touching pairs that have a handler
+	// registered, across steps, so Begin/Separate only fire on change
+	arbiters map[pairKey]*arbiter
+	// pairHandlers holds handlers registered for a specific pair of shapes
+	pairHandlers map[pairKey]CollisionHandler
+	// shapeHandlers holds handlers registered for any collision involving
+	// a given shape
+	shapeHandlers map[Shape]CollisionHandler
 }
 
 // NewSpatialHash returns a new *SpatialHash
 func NewSpatialHash(cellSize int) *SpatialHash {
 	return &SpatialHash{
-		CellSize: cellSize,
-		Hash:     make(map[CellCoord]*Cell),
-		Backref:  make(map[Shape][]*Cell),
+		CellSize:      cellSize,
+		Hash:          make(map[CellCoord]*Cell),
+		Backref:       make(map[Shape]Backref),
+		Bodies:        make(map[Shape]*Body),
+		arbiters:      make(map[pairKey]*arbiter),
+		pairHandlers:  make(map[pairKey]CollisionHandler),
+		shapeHandlers: make(map[Shape]CollisionHandler),
 	}
 }
 
-// Add adds a shape to the spatial hash
-func (s *SpatialHash) Add(shape Shape) {
-	x1, y1, x2, y2 := shape.GetBounds()
+// cellsForBounds returns the set of CellCoord that the bounds x1,y1 to
+// x2,y2 span
+func (s *SpatialHash) cellsForBounds(x1, y1, x2, y2 float64) Backref {
+	cells := make(Backref)
 
 	// make sure big shapes are constrained properly
 	xStep := x2 - x1
@@ -89,15 +114,18 @@ func (s *SpatialHash) Add(shape Shape) {
 	}
 	for x := x1; x <= x2; x += xStep {
 		for y := y1; y <= y2; y += yStep {
-			hashPos := CellCoord{
-				int(math.Floor(x / float64(s.CellSize))),
-				int(math.Floor(y / float64(s.CellSize))),
-			}
-			if _, ok := s.Hash[hashPos]; !ok {
-				s.Hash[hashPos] = &Cell{Shapes: make(map[Shape]Shape)}
+			cells[s.cellCoordAt(x, y)] = true
+			// a zero-width or zero-height span (e.g. an axis-aligned
+			// SegmentShape) leaves the loop below after its first sample,
+			// so cover every cell along it here
+			if xStep == 0 || yStep == 0 {
+				lo, hi := s.cellCoordAt(x1, y1), s.cellCoordAt(x2, y2)
+				for cx := lo.X; cx <= hi.X; cx++ {
+					for cy := lo.Y; cy <= hi.Y; cy++ {
+						cells[CellCoord{cx, cy}] = true
+					}
+				}
 			}
-			s.Hash[hashPos].Shapes[shape] = shape                        // add shape to cell
-			s.Backref[shape] = append(s.Backref[shape], s.Hash[hashPos]) // add cell to backref
 
 			if xStep == 0 || yStep == 0 {
 				goto done
@@ -106,35 +134,95 @@ func (s *SpatialHash) Add(shape Shape) {
 	}
 done:
 
+	return cells
+}
+
+// Add adds a shape to the spatial hash
+func (s *SpatialHash) Add(shape Shape) {
+	x1, y1, x2, y2 := shape.GetBounds()
+	cells := s.cellsForBounds(x1, y1, x2, y2)
+
+	for coord := range cells {
+		cell, ok := s.Hash[coord]
+		if !ok {
+			cell = &Cell{Shapes: make(map[Shape]Shape)}
+			s.Hash[coord] = cell
+		}
+		cell.Shapes[shape] = shape
+	}
+	s.Backref[shape] = cells
+
 	shape.SetHash(s)
 }
 
-// Remove removes a shape from the spatial hash
+// Remove removes a shape from the spatial hash, returning nil on success
+// or ErrShapeNotFound if the shape wasn't hashed
 func (s *SpatialHash) Remove(shape Shape) error {
-	if cells, ok := s.Backref[shape]; ok {
-		for _, cell := range cells {
+	cells, ok := s.Backref[shape]
+	if !ok {
+		return ErrShapeNotFound
+	}
+
+	for coord := range cells {
+		if cell, ok := s.Hash[coord]; ok {
+			delete(cell.Shapes, shape)
+		}
+	}
+	delete(s.Backref, shape)
+
+	return nil
+}
+
+// Update refreshes shape's position in the hash after its bounds have
+// changed, touching only the cells that stopped or started covering it
+// instead of clearing and rehashing every cell it covers like Remove+Add
+func (s *SpatialHash) Update(shape Shape) {
+	x1, y1, x2, y2 := shape.GetBounds()
+	newCells := s.cellsForBounds(x1, y1, x2, y2)
+	oldCells := s.Backref[shape]
+
+	for coord := range oldCells {
+		if newCells[coord] {
+			continue
+		}
+		if cell, ok := s.Hash[coord]; ok {
 			delete(cell.Shapes, shape)
 		}
-		s.Backref[shape] = nil
 	}
+	for coord := range newCells {
+		if oldCells[coord] {
+			continue
+		}
+		cell, ok := s.Hash[coord]
+		if !ok {
+			cell = &Cell{Shapes: make(map[Shape]Shape)}
+			s.Hash[coord] = cell
+		}
+		cell.Shapes[shape] = shape
+	}
+	s.Backref[shape] = newCells
 
-	return ErrShapeNotFound
+	shape.SetHash(s)
 }
 
-// GetCollisionCandidates returns a list of all shapes in the same cells as shape
+// GetCollisionCandidates returns a list of all shapes in the same cells as
+// shape, excluding any whose Body's Category/Mask rules out a collision
+// with shape's Body
 func (s *SpatialHash) GetCollisionCandidates(shape Shape) []Shape {
 	shapesMap := make(map[Shape]bool)
-	if cells, ok := s.Backref[shape]; ok {
-		for _, cell := range cells {
+	for coord := range s.Backref[shape] {
+		if cell, ok := s.Hash[coord]; ok {
 			for _, sh := range cell.Shapes {
 				shapesMap[sh] = true
 			}
 		}
 	}
 	delete(shapesMap, shape)
-	shapes := make([]Shape, len(shapesMap))
+	shapes := make([]Shape, 0, len(shapesMap))
 	for k := range shapesMap {
-		shapes = append(shapes, k)
+		if s.canCollide(shape, k) {
+			shapes = append(shapes, k)
+		}
 	}
 	return shapes
 }
@@ -143,6 +231,9 @@ func (s *SpatialHash) GetCollisionCandidates(shape Shape) []Shape {
 type CollisionData struct {
 	Other            Shape
 	SeparatingVector *Vector
+	// Normal is the surface normal at the point of impact, pointing away
+	// from Other. It's only populated by swept queries (MoveSwept, Sweep)
+	Normal *Vector
 }
 
 func collisionRectRect(r1, r2 *RectangleShape) *Vector {
@@ -230,11 +321,19 @@ func collisionRectCirc(r1 *RectangleShape, c1 *CircleShape) *Vector {
 
 func collisionCircCirc(c1, c2 *CircleShape) *Vector {
 	dist := c1.Pos.Sub(c2.Pos)
-	depth := c1.Radius + c2.Radius - dist.Length()
+	length := dist.Length()
+	depth := c1.Radius + c2.Radius - length
 	if depth < 0 {
 		return &Vector{0, 0}
 	}
 
+	// concentric circles have no direction to separate along; Normalize
+	// would otherwise return the zero vector here regardless of depth,
+	// hiding a genuine (and often total) overlap, so pick an arbitrary axis
+	if length == 0 {
+		return &Vector{depth, 0}
+	}
+
 	return dist.Normalize().Mult(depth)
 }
 
@@ -253,6 +352,10 @@ func (s *SpatialHash) CheckCollisions(shape Shape) []CollisionData {
 				col = collisionRectRect(typed, other)
 			case *CircleShape:
 				col = collisionRectCirc(typed, other)
+			case *PolygonShape:
+				col = collisionPolyRect(other, typed).Mult(-1)
+			case *SegmentShape:
+				col = collisionSegRect(other, typed).Mult(-1)
 			default:
 				// TODO error
 			}
@@ -268,6 +371,49 @@ func (s *SpatialHash) CheckCollisions(shape Shape) []CollisionData {
 				col = collisionRectCirc(other, typed).Mult(-1)
 			case *CircleShape:
 				col = collisionCircCirc(typed, other)
+			case *PolygonShape:
+				col = collisionPolyCirc(other, typed).Mult(-1)
+			case *SegmentShape:
+				col = collisionSegCirc(other, typed).Mult(-1)
+			default:
+				// TODO error
+			}
+			if col != nil && col.Length() > 0 {
+				collisions = append(collisions, CollisionData{Other: candidate, SeparatingVector: col})
+			}
+		}
+	case *PolygonShape:
+		for _, candidate := range candidates {
+			var col *Vector
+			switch other := candidate.(type) {
+			case *RectangleShape:
+				col = collisionPolyRect(typed, other)
+			case *CircleShape:
+				col = collisionPolyCirc(typed, other)
+			case *PolygonShape:
+				col = collisionPolyPoly(typed, other)
+			case *SegmentShape:
+				col = collisionPolySeg(typed, other)
+			default:
+				// TODO error
+			}
+			if col != nil && col.Length() > 0 {
+				collisions = append(collisions, CollisionData{Other: candidate, SeparatingVector: col})
+			}
+		}
+	case *SegmentShape:
+		for _, candidate := range candidates {
+			var col *Vector
+			switch other := candidate.(type) {
+			case *RectangleShape:
+				col = collisionSegRect(typed, other)
+			case *CircleShape:
+				col = collisionSegCirc(typed, other)
+			case *PolygonShape:
+				col = collisionPolySeg(other, typed).Mult(-1)
+			case *SegmentShape:
+				// segments are infinitely thin; use Intersect for crossing
+				// tests instead of trying to separate two lines
 			default:
 				// TODO error
 			}
@@ -324,8 +470,7 @@ func (ci *CircleShape) Move(x, y float64) {
 	ci.Pos.X += x
 	ci.Pos.Y += y
 	hash := ci.GetHash()
-	hash.Remove(ci)
-	hash.Add(ci)
+	hash.Update(ci)
 }
 
 // MoveTo moves the CircleShape to x and y
@@ -333,8 +478,7 @@ func (ci *CircleShape) MoveTo(x, y float64) {
 	ci.Pos.X = x
 	ci.Pos.Y = y
 	hash := ci.GetHash()
-	hash.Remove(ci)
-	hash.Add(ci)
+	hash.Update(ci)
 }
 
 // SetHash sets the hash
@@ -376,8 +520,7 @@ func (re *RectangleShape) Move(x, y float64) {
 	re.Pos.X += x
 	re.Pos.Y += y
 	hash := re.GetHash()
-	hash.Remove(re)
-	hash.Add(re)
+	hash.Update(re)
 }
 
 // MoveTo moves the RectangleShape to x and y
@@ -385,8 +528,7 @@ func (re *RectangleShape) MoveTo(x, y float64) {
 	re.Pos.X = x
 	re.Pos.Y = y
 	hash := re.GetHash()
-	hash.Remove(re)
-	hash.Add(re)
+	hash.Update(re)
 }
 
 // SetHash sets the hash
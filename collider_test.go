@@ -0,0 +1,81 @@
+package collider
+
+import "testing"
+
+func TestSpatialHashRemove(t *testing.T) {
+	hash := NewSpatialHash(16)
+	shape := hash.NewCircleShape(0, 0, 5)
+
+	if err := hash.Remove(shape); err != nil {
+		t.Fatalf("Remove = %v, want nil", err)
+	}
+	if _, ok := hash.Backref[shape]; ok {
+		t.Fatalf("expected shape's backref to be gone after Remove")
+	}
+	if err := hash.Remove(shape); err != ErrShapeNotFound {
+		t.Fatalf("Remove on an already-removed shape = %v, want ErrShapeNotFound", err)
+	}
+}
+
+func TestSpatialHashUpdateOnlyTouchesChangedCells(t *testing.T) {
+	hash := NewSpatialHash(16)
+	shape := hash.NewCircleShape(0, 0, 5)
+
+	oldCells := hash.Backref[shape]
+	for coord := range oldCells {
+		if _, ok := hash.Hash[coord].Shapes[shape]; !ok {
+			t.Fatalf("shape missing from cell %v before move", coord)
+		}
+	}
+
+	shape.MoveTo(1000, 1000)
+
+	for coord := range oldCells {
+		if cell, ok := hash.Hash[coord]; ok {
+			if _, stillThere := cell.Shapes[shape]; stillThere {
+				t.Fatalf("cell %v still references shape after it moved away", coord)
+			}
+		}
+	}
+
+	newCells := hash.Backref[shape]
+	for coord := range newCells {
+		if _, ok := hash.Hash[coord].Shapes[shape]; !ok {
+			t.Fatalf("shape missing from cell %v after move", coord)
+		}
+	}
+}
+
+func TestGetCollisionCandidatesFiltersByCategoryMask(t *testing.T) {
+	const (
+		categoryBullet CollisionCategory = 1 << iota
+		categoryWall
+	)
+
+	hash := NewSpatialHash(16)
+	bulletA := hash.NewCircleShape(0, 0, 5)
+	bulletB := hash.NewCircleShape(5, 0, 5)
+	wall := hash.NewCircleShape(0, 5, 5)
+
+	// bullets ignore each other but still collide with walls
+	hash.NewBody(bulletA, 1, 0, 0, categoryBullet, CollisionMask(categoryWall))
+	hash.NewBody(bulletB, 1, 0, 0, categoryBullet, CollisionMask(categoryWall))
+	hash.NewBody(wall, 0, 0, 0, categoryWall, CategoryAll)
+
+	candidates := hash.GetCollisionCandidates(bulletA)
+	for _, c := range candidates {
+		if c == bulletB {
+			t.Fatalf("expected bulletA's mask to exclude bulletB, got %v", candidates)
+		}
+	}
+
+	foundWall := false
+	for _, c := range candidates {
+		if c == wall {
+			foundWall = true
+		}
+	}
+	if !foundWall {
+		t.Fatalf("expected bulletA's candidates to include the wall, got %v", candidates)
+	}
+}
@@ -0,0 +1,423 @@
+package collider
+
+import "math"
+
+// PolygonShape is a convex polygon. Vertices are specified relative to Pos,
+// clockwise when Y increases downwards (the equivalent of counter-clockwise
+// in a standard Y-up Cartesian system, and the winding Tiled exports), which
+// keeps edge normals pointing outwards for the SAT tests below.
+type PolygonShape struct {
+	// Center point
+	Pos *Vector
+	// Vertices are relative to Pos and unrotated
+	Vertices    []*Vector
+	Angle       float64
+	SpatialHash *SpatialHash
+}
+
+// SegmentShape is a single straight line segment, useful for slopes and
+// raycast occluders. A and B are its endpoints, relative to Pos
+type SegmentShape struct {
+	// Center point (the midpoint of the segment)
+	Pos         *Vector
+	A, B        *Vector
+	SpatialHash *SpatialHash
+}
+
+// NewPolygonShape creates, then adds a new PolygonShape to the hash before
+// returning it. vertices are relative to x,y
+func (s *SpatialHash) NewPolygonShape(x, y float64, vertices []*Vector) *PolygonShape {
+	po := &PolygonShape{
+		Pos:      &Vector{x, y},
+		Vertices: vertices,
+	}
+	s.Add(po)
+	return po
+}
+
+// NewSegmentShape creates, then adds a new SegmentShape to the hash before
+// returning it
+func (s *SpatialHash) NewSegmentShape(x1, y1, x2, y2 float64) *SegmentShape {
+	mid := &Vector{(x1 + x2) / 2, (y1 + y2) / 2}
+	se := &SegmentShape{
+		Pos: mid,
+		A:   (&Vector{x1, y1}).Sub(mid),
+		B:   (&Vector{x2, y2}).Sub(mid),
+	}
+	s.Add(se)
+	return se
+}
+
+// worldVertices returns the polygon's vertices in world space, rotated by
+// Angle and translated by Pos
+func (po *PolygonShape) worldVertices() []*Vector {
+	verts := make([]*Vector, len(po.Vertices))
+	for i, v := range po.Vertices {
+		verts[i] = po.Pos.Add(v.Rotate(po.Angle))
+	}
+	return verts
+}
+
+// axes returns the outward-facing normal of every edge of the polygon
+func (po *PolygonShape) axes() []*Vector {
+	verts := po.worldVertices()
+	axes := make([]*Vector, len(verts))
+	for i := range verts {
+		edge := verts[(i+1)%len(verts)].Sub(verts[i])
+		axes[i] = (&Vector{edge.Y, -edge.X}).Normalize()
+	}
+	return axes
+}
+
+// GetPosition returns the Pos of the PolygonShape
+func (po *PolygonShape) GetPosition() *Vector {
+	return po.Pos
+}
+
+// GetBounds returns the axis-aligned bounding box of the rotated polygon
+func (po *PolygonShape) GetBounds() (float64, float64, float64, float64) {
+	verts := po.worldVertices()
+	minX, minY := verts[0].X, verts[0].Y
+	maxX, maxY := minX, minY
+	for _, v := range verts[1:] {
+		minX = math.Min(minX, v.X)
+		minY = math.Min(minY, v.Y)
+		maxX = math.Max(maxX, v.X)
+		maxY = math.Max(maxY, v.Y)
+	}
+	return minX, minY, maxX, maxY
+}
+
+// Move moves the PolygonShape by x and y
+func (po *PolygonShape) Move(x, y float64) {
+	po.Pos.X += x
+	po.Pos.Y += y
+	hash := po.GetHash()
+	hash.Update(po)
+}
+
+// MoveTo moves the PolygonShape to x and y
+func (po *PolygonShape) MoveTo(x, y float64) {
+	po.Pos.X = x
+	po.Pos.Y = y
+	hash := po.GetHash()
+	hash.Update(po)
+}
+
+// MoveSwept moves po by x,y, stopping at the earliest time of impact found
+// along the way. Polygons have no closed-form swept solver, so this
+// bisects on the ordinary overlap test instead of solving the TOI directly
+func (po *PolygonShape) MoveSwept(x, y float64) (float64, []CollisionData) {
+	t, hits := sweepFallback(po, x, y)
+	po.Move(x*t, y*t)
+	return t, hits
+}
+
+// SetHash sets the hash
+func (po *PolygonShape) SetHash(s *SpatialHash) {
+	po.SpatialHash = s
+}
+
+// GetHash gets the hash
+func (po *PolygonShape) GetHash() *SpatialHash {
+	return po.SpatialHash
+}
+
+// GetPosition returns the Pos of the SegmentShape
+func (se *SegmentShape) GetPosition() *Vector {
+	return se.Pos
+}
+
+// GetBounds returns the axis-aligned bounding box of the SegmentShape
+func (se *SegmentShape) GetBounds() (float64, float64, float64, float64) {
+	a, b := se.Pos.Add(se.A), se.Pos.Add(se.B)
+	return math.Min(a.X, b.X), math.Min(a.Y, b.Y), math.Max(a.X, b.X), math.Max(a.Y, b.Y)
+}
+
+// Move moves the SegmentShape by x and y
+func (se *SegmentShape) Move(x, y float64) {
+	se.Pos.X += x
+	se.Pos.Y += y
+	hash := se.GetHash()
+	hash.Update(se)
+}
+
+// MoveTo moves the SegmentShape to x and y
+func (se *SegmentShape) MoveTo(x, y float64) {
+	se.Pos.X = x
+	se.Pos.Y = y
+	hash := se.GetHash()
+	hash.Update(se)
+}
+
+// MoveSwept moves se by x,y, stopping at the earliest time of impact found
+// along the way. Segments have no closed-form swept solver, so this
+// bisects on the ordinary overlap test instead of solving the TOI directly
+func (se *SegmentShape) MoveSwept(x, y float64) (float64, []CollisionData) {
+	t, hits := sweepFallback(se, x, y)
+	se.Move(x*t, y*t)
+	return t, hits
+}
+
+// SetHash sets the hash
+func (se *SegmentShape) SetHash(s *SpatialHash) {
+	se.SpatialHash = s
+}
+
+// GetHash gets the hash
+func (se *SegmentShape) GetHash() *SpatialHash {
+	return se.SpatialHash
+}
+
+// axis returns the single outward-facing normal axis of the segment
+func (se *SegmentShape) axis() *Vector {
+	dir := se.B.Sub(se.A)
+	return (&Vector{dir.Y, -dir.X}).Normalize()
+}
+
+// worldPoints returns the segment's two endpoints in world space
+func (se *SegmentShape) worldPoints() []*Vector {
+	return []*Vector{se.Pos.Add(se.A), se.Pos.Add(se.B)}
+}
+
+// rectVertices returns the RectangleShape's four corners in world space,
+// ordered to match PolygonShape's winding so the two can share SAT code
+func rectVertices(re *RectangleShape) []*Vector {
+	left, up, right, down := re.GetBounds()
+	return []*Vector{
+		{left, up},
+		{right, up},
+		{right, down},
+		{left, down},
+	}
+}
+
+// rectAxes returns the two unique edge normals of an axis-aligned rectangle
+func rectAxes() []*Vector {
+	return []*Vector{{1, 0}, {0, 1}}
+}
+
+// projectOntoAxis returns the min/max projection of vertices onto axis
+func projectOntoAxis(vertices []*Vector, axis *Vector) (min, max float64) {
+	min = vertices[0].Dot(axis)
+	max = min
+	for _, v := range vertices[1:] {
+		p := v.Dot(axis)
+		if p < min {
+			min = p
+		}
+		if p > max {
+			max = p
+		}
+	}
+	return
+}
+
+// satSeparatingVector runs the Separating Axis Theorem against axes, using
+// the vertex sets verticesA/verticesB, with centerA/centerB used only to
+// orient the result. It returns the minimum-overlap axis scaled by the
+// overlap, pointing from B towards A, or a zero vector if any axis
+// separates the two sets
+func satSeparatingVector(verticesA []*Vector, verticesB []*Vector, axes []*Vector, centerA, centerB *Vector) *Vector {
+	bestOverlap := math.Inf(1)
+	var bestAxis *Vector
+
+	for _, axis := range axes {
+		minA, maxA := projectOntoAxis(verticesA, axis)
+		minB, maxB := projectOntoAxis(verticesB, axis)
+
+		// A SegmentShape (or any other vertex set with no extent along
+		// this particular axis, e.g. its own normal) always projects to
+		// a single point here, which makes the overlap-by-subtraction
+		// below come out <=0 even when that point sits squarely inside
+		// the other side's interval. Test for strict containment on
+		// that axis instead of bailing, and leave it out of the
+		// overlap/depth comparison since it can't meaningfully push
+		// anything apart.
+		if minA == maxA {
+			if minA <= minB || minA >= maxB {
+				return &Vector{0, 0}
+			}
+			continue
+		}
+		if minB == maxB {
+			if minB <= minA || minB >= maxA {
+				return &Vector{0, 0}
+			}
+			continue
+		}
+
+		overlap := math.Min(maxA, maxB) - math.Max(minA, minB)
+		if overlap <= 0 {
+			return &Vector{0, 0}
+		}
+		if overlap < bestOverlap {
+			bestOverlap = overlap
+			bestAxis = axis
+		}
+	}
+
+	if bestAxis == nil {
+		return &Vector{0, 0}
+	}
+
+	// orient the separating vector so it points from B towards A
+	if centerA.Sub(centerB).Dot(bestAxis) < 0 {
+		bestAxis = bestAxis.Mult(-1)
+	}
+	return bestAxis.Mult(bestOverlap)
+}
+
+// polygonCenter returns the average of a polygon's world vertices, good
+// enough to pick which side of a separating axis is "outwards"
+func polygonCenter(vertices []*Vector) *Vector {
+	center := &Vector{0, 0}
+	for _, v := range vertices {
+		center = center.Add(v)
+	}
+	return center.Mult(1 / float64(len(vertices)))
+}
+
+func collisionPolyPoly(p1, p2 *PolygonShape) *Vector {
+	v1, v2 := p1.worldVertices(), p2.worldVertices()
+	axes := append(append([]*Vector{}, p1.axes()...), p2.axes()...)
+	return satSeparatingVector(v1, v2, axes, p1.Pos, p2.Pos)
+}
+
+func collisionPolyRect(p1 *PolygonShape, r1 *RectangleShape) *Vector {
+	v1, v2 := p1.worldVertices(), rectVertices(r1)
+	axes := append(append([]*Vector{}, p1.axes()...), rectAxes()...)
+	return satSeparatingVector(v1, v2, axes, p1.Pos, r1.Pos)
+}
+
+func collisionPolySeg(p1 *PolygonShape, s1 *SegmentShape) *Vector {
+	v1, v2 := p1.worldVertices(), s1.worldPoints()
+	axes := append(append([]*Vector{}, p1.axes()...), s1.axis())
+	return satSeparatingVector(v1, v2, axes, p1.Pos, s1.Pos)
+}
+
+// collisionPolyCirc uses polygon edge normals plus the axis from the
+// circle's center to its closest polygon vertex, per the classic
+// polygon-vs-circle SAT special case
+func collisionPolyCirc(p1 *PolygonShape, c1 *CircleShape) *Vector {
+	verts := p1.worldVertices()
+
+	closest := verts[0]
+	closestDist := math.Inf(1)
+	for _, v := range verts {
+		d := v.Sub(c1.Pos).Length()
+		if d < closestDist {
+			closestDist = d
+			closest = v
+		}
+	}
+	axes := append(append([]*Vector{}, p1.axes()...), closest.Sub(c1.Pos).Normalize())
+
+	bestOverlap := math.Inf(1)
+	var bestAxis *Vector
+	for _, axis := range axes {
+		minP, maxP := projectOntoAxis(verts, axis)
+		c := c1.Pos.Dot(axis)
+		minC, maxC := c-c1.Radius, c+c1.Radius
+
+		overlap := math.Min(maxP, maxC) - math.Max(minP, minC)
+		if overlap <= 0 {
+			return &Vector{0, 0}
+		}
+		if overlap < bestOverlap {
+			bestOverlap = overlap
+			bestAxis = axis
+		}
+	}
+	if bestAxis == nil {
+		return &Vector{0, 0}
+	}
+	if polygonCenter(verts).Sub(c1.Pos).Dot(bestAxis) < 0 {
+		bestAxis = bestAxis.Mult(-1)
+	}
+	return bestAxis.Mult(bestOverlap)
+}
+
+func collisionSegRect(s1 *SegmentShape, r1 *RectangleShape) *Vector {
+	v1, v2 := s1.worldPoints(), rectVertices(r1)
+	axes := append([]*Vector{s1.axis()}, rectAxes()...)
+	return satSeparatingVector(v1, v2, axes, s1.Pos, r1.Pos)
+}
+
+func collisionSegCirc(s1 *SegmentShape, c1 *CircleShape) *Vector {
+	verts := s1.worldPoints()
+
+	closest := verts[0]
+	if verts[1].Sub(c1.Pos).Length() < closest.Sub(c1.Pos).Length() {
+		closest = verts[1]
+	}
+	axes := []*Vector{s1.axis(), closest.Sub(c1.Pos).Normalize()}
+
+	bestOverlap := math.Inf(1)
+	var bestAxis *Vector
+	for _, axis := range axes {
+		minS, maxS := projectOntoAxis(verts, axis)
+		c := c1.Pos.Dot(axis)
+		minC, maxC := c-c1.Radius, c+c1.Radius
+
+		// the segment has zero extent along its own normal axis, so it
+		// always projects to a single point there; overlap-by-subtraction
+		// would read that as <=0 (separated) even when the circle is
+		// centered squarely on the segment, so test strict containment
+		// on that axis instead of bailing
+		if minS == maxS {
+			if minS <= minC || minS >= maxC {
+				return &Vector{0, 0}
+			}
+			continue
+		}
+
+		overlap := math.Min(maxS, maxC) - math.Max(minS, minC)
+		if overlap <= 0 {
+			return &Vector{0, 0}
+		}
+		if overlap < bestOverlap {
+			bestOverlap = overlap
+			bestAxis = axis
+		}
+	}
+	if bestAxis == nil {
+		return &Vector{0, 0}
+	}
+	if s1.Pos.Sub(c1.Pos).Dot(bestAxis) < 0 {
+		bestAxis = bestAxis.Mult(-1)
+	}
+	return bestAxis.Mult(bestOverlap)
+}
+
+// SegmentIntersection is the result of a parametric segment-vs-segment test
+type SegmentIntersection struct {
+	Point *Vector
+	// T is how far along se (0 at A, 1 at B) the intersection occurred
+	T float64
+}
+
+// Intersect computes the parametric intersection of se and other, returning
+// the point they cross at (and how far along se that is) if they do
+func (se *SegmentShape) Intersect(other *SegmentShape) (*SegmentIntersection, bool) {
+	p, r := se.Pos.Add(se.A), se.B.Sub(se.A)
+	q, sVec := other.Pos.Add(other.A), other.B.Sub(other.A)
+
+	rxs := r.X*sVec.Y - r.Y*sVec.X
+	if rxs == 0 {
+		return nil, false // parallel (or collinear, which we treat as a non-hit)
+	}
+
+	qp := q.Sub(p)
+	t := (qp.X*sVec.Y - qp.Y*sVec.X) / rxs
+	u := (qp.X*r.Y - qp.Y*r.X) / rxs
+
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return nil, false
+	}
+
+	return &SegmentIntersection{
+		Point: p.Add(r.Mult(t)),
+		T:     t,
+	}, true
+}
@@ -0,0 +1,121 @@
+package collider
+
+import "testing"
+
+func square(x, y, half float64) *PolygonShape {
+	return &PolygonShape{
+		Pos: &Vector{x, y},
+		Vertices: []*Vector{
+			{-half, -half}, {half, -half}, {half, half}, {-half, half},
+		},
+	}
+}
+
+func TestCollisionPolyPoly(t *testing.T) {
+	a := square(0, 0, 10)
+
+	tests := []struct {
+		name      string
+		b         *PolygonShape
+		wantEmpty bool
+	}{
+		{"overlapping", square(5, 0, 10), false},
+		{"separated", square(50, 0, 10), true},
+		{"touching edges", square(20, 0, 10), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := collisionPolyPoly(a, tt.b)
+			if (col.Length() == 0) != tt.wantEmpty {
+				t.Fatalf("collisionPolyPoly = %v, wantEmpty %v", col, tt.wantEmpty)
+			}
+		})
+	}
+}
+
+func TestCollisionPolyRect(t *testing.T) {
+	poly := square(0, 0, 10)
+
+	tests := []struct {
+		name      string
+		rect      *RectangleShape
+		wantEmpty bool
+	}{
+		{"overlapping", &RectangleShape{Pos: &Vector{5, 0}, Width: 20, Height: 20}, false},
+		{"separated", &RectangleShape{Pos: &Vector{50, 0}, Width: 20, Height: 20}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := collisionPolyRect(poly, tt.rect)
+			if (col.Length() == 0) != tt.wantEmpty {
+				t.Fatalf("collisionPolyRect = %v, wantEmpty %v", col, tt.wantEmpty)
+			}
+		})
+	}
+}
+
+func TestCollisionPolyCirc(t *testing.T) {
+	poly := square(0, 0, 10)
+
+	tests := []struct {
+		name      string
+		circ      *CircleShape
+		wantEmpty bool
+	}{
+		{"overlapping", &CircleShape{Pos: &Vector{15, 0}, Radius: 10}, false},
+		{"separated", &CircleShape{Pos: &Vector{50, 0}, Radius: 10}, true},
+		{"circle inside poly", &CircleShape{Pos: &Vector{0, 0}, Radius: 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := collisionPolyCirc(poly, tt.circ)
+			if (col.Length() == 0) != tt.wantEmpty {
+				t.Fatalf("collisionPolyCirc = %v, wantEmpty %v", col, tt.wantEmpty)
+			}
+		})
+	}
+}
+
+func TestCollisionSegRect(t *testing.T) {
+	mid := &Vector{0, 0}
+	seg := &SegmentShape{Pos: mid, A: (&Vector{-20, 0}).Sub(mid), B: (&Vector{20, 0}).Sub(mid)}
+
+	tests := []struct {
+		name      string
+		rect      *RectangleShape
+		wantEmpty bool
+	}{
+		{"overlapping", &RectangleShape{Pos: &Vector{0, 0}, Width: 10, Height: 10}, false},
+		{"separated", &RectangleShape{Pos: &Vector{0, 50}, Width: 10, Height: 10}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			col := collisionSegRect(seg, tt.rect)
+			if (col.Length() == 0) != tt.wantEmpty {
+				t.Fatalf("collisionSegRect = %v, wantEmpty %v", col, tt.wantEmpty)
+			}
+		})
+	}
+}
+
+func TestSegmentIntersect(t *testing.T) {
+	mid1 := &Vector{0, 0}
+	a := &SegmentShape{Pos: mid1, A: (&Vector{-10, 0}).Sub(mid1), B: (&Vector{10, 0}).Sub(mid1)}
+
+	mid2 := &Vector{0, 0}
+	crossing := &SegmentShape{Pos: mid2, A: (&Vector{0, -10}).Sub(mid2), B: (&Vector{0, 10}).Sub(mid2)}
+
+	mid3 := &Vector{0, 50}
+	parallel := &SegmentShape{Pos: mid3, A: (&Vector{-10, 50}).Sub(mid3), B: (&Vector{10, 50}).Sub(mid3)}
+
+	if _, ok := a.Intersect(crossing); !ok {
+		t.Fatalf("expected crossing segments to intersect")
+	}
+	if _, ok := a.Intersect(parallel); ok {
+		t.Fatalf("expected parallel, non-intersecting segments to report no intersection")
+	}
+}
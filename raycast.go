@@ -0,0 +1,286 @@
+package collider
+
+import "math"
+
+// RayHit describes where a ray met a Shape
+type RayHit struct {
+	Shape    Shape
+	Point    *Vector
+	Normal   *Vector
+	Distance float64
+}
+
+// rayCircle solves |origin+t*dir-c|^2=r^2 for the closest t in [0,maxDist],
+// returning the distance and outward normal at the hit point
+func rayCircle(origin, dir *Vector, maxDist float64, c *CircleShape) (float64, *Vector, bool) {
+	p := origin.Sub(c.Pos)
+
+	a := dir.Dot(dir)
+	if a == 0 {
+		return 0, nil, false
+	}
+	b := 2 * p.Dot(dir)
+	cc := p.Dot(p) - c.Radius*c.Radius
+
+	disc := b*b - 4*a*cc
+	if disc < 0 {
+		return 0, nil, false
+	}
+	sq := math.Sqrt(disc)
+	t := (-b - sq) / (2 * a)
+	if t < 0 {
+		// origin may already be inside the circle; try the far root
+		t = (-b + sq) / (2 * a)
+		if t < 0 {
+			return 0, nil, false
+		}
+	}
+	if t > maxDist {
+		return 0, nil, false
+	}
+
+	point := origin.Add(dir.Mult(t))
+	return t, point.Sub(c.Pos).Normalize(), true
+}
+
+// rayRect finds the closest entry point of the ray against the rectangle's
+// AABB using the slab method, returning the distance and face normal
+func rayRect(origin, dir *Vector, maxDist float64, r *RectangleShape) (float64, *Vector, bool) {
+	left, up, right, down := r.GetBounds()
+
+	tEnterX, tExitX := math.Inf(-1), math.Inf(1)
+	if dir.X != 0 {
+		tx1 := (left - origin.X) / dir.X
+		tx2 := (right - origin.X) / dir.X
+		tEnterX, tExitX = math.Min(tx1, tx2), math.Max(tx1, tx2)
+	} else if origin.X < left || origin.X > right {
+		return 0, nil, false
+	}
+
+	tEnterY, tExitY := math.Inf(-1), math.Inf(1)
+	if dir.Y != 0 {
+		ty1 := (up - origin.Y) / dir.Y
+		ty2 := (down - origin.Y) / dir.Y
+		tEnterY, tExitY = math.Min(ty1, ty2), math.Max(ty1, ty2)
+	} else if origin.Y < up || origin.Y > down {
+		return 0, nil, false
+	}
+
+	tEnter := math.Max(tEnterX, tEnterY)
+	tExit := math.Min(tExitX, tExitY)
+	if tEnter > tExit || tEnter > maxDist || tExit < 0 {
+		return 0, nil, false
+	}
+	if tEnter < 0 {
+		tEnter = 0
+	}
+
+	if tEnterX >= tEnterY {
+		if dir.X > 0 {
+			return tEnter, &Vector{-1, 0}, true
+		}
+		return tEnter, &Vector{1, 0}, true
+	}
+	if dir.Y > 0 {
+		return tEnter, &Vector{0, -1}, true
+	}
+	return tEnter, &Vector{0, 1}, true
+}
+
+// raySegment finds the parametric intersection of the ray with the segment
+// a-b, returning the distance and the segment's normal (oriented against
+// the ray direction)
+func raySegment(origin, dir *Vector, maxDist float64, a, b *Vector) (float64, *Vector, bool) {
+	s := b.Sub(a)
+	rxs := dir.X*s.Y - dir.Y*s.X
+	if rxs == 0 {
+		return 0, nil, false
+	}
+
+	qp := a.Sub(origin)
+	t := (qp.X*s.Y - qp.Y*s.X) / rxs
+	u := (qp.X*dir.Y - qp.Y*dir.X) / rxs
+	if t < 0 || t > maxDist || u < 0 || u > 1 {
+		return 0, nil, false
+	}
+
+	normal := (&Vector{s.Y, -s.X}).Normalize()
+	if normal.Dot(dir) > 0 {
+		normal = normal.Mult(-1)
+	}
+	return t, normal, true
+}
+
+func rayPolygon(origin, dir *Vector, maxDist float64, p *PolygonShape) (float64, *Vector, bool) {
+	verts := p.worldVertices()
+	bestDist := math.Inf(1)
+	var bestNormal *Vector
+	for i := range verts {
+		a, b := verts[i], verts[(i+1)%len(verts)]
+		if dist, normal, ok := raySegment(origin, dir, maxDist, a, b); ok && dist < bestDist {
+			bestDist, bestNormal = dist, normal
+		}
+	}
+	if bestNormal == nil {
+		return 0, nil, false
+	}
+	return bestDist, bestNormal, true
+}
+
+func raySegmentShape(origin, dir *Vector, maxDist float64, s *SegmentShape) (float64, *Vector, bool) {
+	a, b := s.Pos.Add(s.A), s.Pos.Add(s.B)
+	return raySegment(origin, dir, maxDist, a, b)
+}
+
+// rayShape dispatches to the correct ray-vs-shape test based on shape's
+// concrete type
+func rayShape(origin, dir *Vector, maxDist float64, shape Shape) (float64, *Vector, bool) {
+	switch sh := shape.(type) {
+	case *CircleShape:
+		return rayCircle(origin, dir, maxDist, sh)
+	case *RectangleShape:
+		return rayRect(origin, dir, maxDist, sh)
+	case *PolygonShape:
+		return rayPolygon(origin, dir, maxDist, sh)
+	case *SegmentShape:
+		return raySegmentShape(origin, dir, maxDist, sh)
+	}
+	return 0, nil, false
+}
+
+// Raycast walks the grid cells between origin and origin+dir*maxDist using
+// Amanatides & Woo traversal, testing only the shapes found in each cell,
+// and returns every shape hit at the closest distance found, nearest first.
+// filter may be nil; when set, shapes for which it returns false are
+// skipped entirely
+func (s *SpatialHash) Raycast(origin, dir *Vector, maxDist float64, filter func(Shape) bool) []RayHit {
+	dir = dir.Normalize()
+	dx, dy := dir.X*maxDist, dir.Y*maxDist
+
+	bestDist := maxDist
+	hits := make([]RayHit, 0)
+	tested := make(map[Shape]bool)
+
+	const epsilon = 1e-9
+
+	s.walkCells(origin.X, origin.Y, dx, dy, func(cell CellCoord, tEntry float64) bool {
+		if tEntry*maxDist > bestDist+epsilon {
+			return true
+		}
+		c, ok := s.Hash[cell]
+		if !ok {
+			return false
+		}
+		for candidate := range c.Shapes {
+			if tested[candidate] {
+				continue
+			}
+			tested[candidate] = true
+			if filter != nil && !filter(candidate) {
+				continue
+			}
+
+			dist, normal, ok := rayShape(origin, dir, maxDist, candidate)
+			if !ok {
+				continue
+			}
+			if dist < bestDist-epsilon {
+				bestDist = dist
+				hits = hits[:0]
+				hits = append(hits, RayHit{
+					Shape:    candidate,
+					Point:    origin.Add(dir.Mult(dist)),
+					Normal:   normal,
+					Distance: dist,
+				})
+			} else if dist < bestDist+epsilon {
+				hits = append(hits, RayHit{
+					Shape:    candidate,
+					Point:    origin.Add(dir.Mult(dist)),
+					Normal:   normal,
+					Distance: dist,
+				})
+			}
+		}
+		return false
+	})
+
+	return hits
+}
+
+// shapesInBounds collects every distinct shape hashed into the cells
+// spanned by the given AABB, without requiring the query area itself to be
+// a Shape registered in the hash
+func (s *SpatialHash) shapesInBounds(x1, y1, x2, y2 float64) []Shape {
+	found := make(map[Shape]bool)
+
+	minCell := s.cellCoordAt(x1, y1)
+	maxCell := s.cellCoordAt(x2, y2)
+	for x := minCell.X; x <= maxCell.X; x++ {
+		for y := minCell.Y; y <= maxCell.Y; y++ {
+			if cell, ok := s.Hash[CellCoord{x, y}]; ok {
+				for shape := range cell.Shapes {
+					found[shape] = true
+				}
+			}
+		}
+	}
+
+	shapes := make([]Shape, 0, len(found))
+	for shape := range found {
+		shapes = append(shapes, shape)
+	}
+	return shapes
+}
+
+// QueryRect returns every shape overlapping the axis-aligned rectangle
+// centered at x,y with the given width and height
+func (s *SpatialHash) QueryRect(x, y, w, h float64) []Shape {
+	query := &RectangleShape{Pos: &Vector{x, y}, Width: w, Height: h}
+	left, up, right, down := query.GetBounds()
+
+	results := make([]Shape, 0)
+	for _, shape := range s.shapesInBounds(left, up, right, down) {
+		var col *Vector
+		switch other := shape.(type) {
+		case *RectangleShape:
+			col = collisionRectRect(query, other)
+		case *CircleShape:
+			col = collisionRectCirc(query, other)
+		case *PolygonShape:
+			col = collisionPolyRect(other, query).Mult(-1)
+		case *SegmentShape:
+			col = collisionSegRect(other, query).Mult(-1)
+		}
+		if col != nil && col.Length() > 0 {
+			results = append(results, shape)
+		}
+	}
+	return results
+}
+
+// QueryCircle returns every shape overlapping the circle centered at x,y
+// with the given radius
+func (s *SpatialHash) QueryCircle(x, y, radius float64) []Shape {
+	query := &CircleShape{Pos: &Vector{x, y}, Radius: radius}
+	left, up, right, down := query.GetBounds()
+
+	results := make([]Shape, 0)
+	for _, shape := range s.shapesInBounds(left, up, right, down) {
+		var col *Vector
+		switch other := shape.(type) {
+		case *RectangleShape:
+			col = collisionRectCirc(other, query).Mult(-1)
+		case *CircleShape:
+			col = collisionCircCirc(query, other)
+		case *PolygonShape:
+			col = collisionPolyCirc(other, query).Mult(-1)
+		case *SegmentShape:
+			col = collisionSegCirc(other, query).Mult(-1)
+		}
+		if col != nil && col.Length() > 0 {
+			results = append(results, shape)
+		}
+	}
+	return results
+}
@@ -0,0 +1,115 @@
+package collider
+
+import "testing"
+
+func TestRayCircle(t *testing.T) {
+	c := &CircleShape{Pos: &Vector{100, 0}, Radius: 10}
+
+	tests := []struct {
+		name    string
+		origin  *Vector
+		dir     *Vector
+		maxDist float64
+		wantHit bool
+	}{
+		{"hits head on", &Vector{0, 0}, &Vector{1, 0}, 200, true},
+		{"misses, passes by", &Vector{0, 50}, &Vector{1, 0}, 200, false},
+		{"too short to reach", &Vector{0, 0}, &Vector{1, 0}, 50, false},
+		{"origin inside circle", &Vector{100, 0}, &Vector{1, 0}, 200, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, ok := rayCircle(tt.origin, tt.dir, tt.maxDist, c)
+			if ok != tt.wantHit {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantHit)
+			}
+		})
+	}
+}
+
+func TestRayRect(t *testing.T) {
+	r := &RectangleShape{Pos: &Vector{100, 0}, Width: 20, Height: 20}
+
+	tests := []struct {
+		name    string
+		origin  *Vector
+		dir     *Vector
+		maxDist float64
+		wantHit bool
+	}{
+		{"hits the face", &Vector{0, 0}, &Vector{1, 0}, 200, true},
+		{"misses, passes above", &Vector{0, 50}, &Vector{1, 0}, 200, false},
+		{"too short to reach", &Vector{0, 0}, &Vector{1, 0}, 50, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, normal, ok := rayRect(tt.origin, tt.dir, tt.maxDist, r)
+			if ok != tt.wantHit {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantHit)
+			}
+			if ok && normal == nil {
+				t.Fatalf("normal is nil on a hit")
+			}
+		})
+	}
+}
+
+func TestRaySegment(t *testing.T) {
+	a, b := &Vector{100, -10}, &Vector{100, 10}
+
+	tests := []struct {
+		name    string
+		origin  *Vector
+		dir     *Vector
+		maxDist float64
+		wantHit bool
+	}{
+		{"crosses the segment", &Vector{0, 0}, &Vector{1, 0}, 200, true},
+		{"passes outside the segment's span", &Vector{0, 50}, &Vector{1, 0}, 200, false},
+		{"parallel to the segment", &Vector{0, 0}, &Vector{0, 1}, 200, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, ok := raySegment(tt.origin, tt.dir, tt.maxDist, a, b)
+			if ok != tt.wantHit {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantHit)
+			}
+		})
+	}
+}
+
+func TestSpatialHashRaycastPicksNearest(t *testing.T) {
+	hash := NewSpatialHash(16)
+	near := hash.NewCircleShape(50, 0, 5)
+	far := hash.NewCircleShape(100, 0, 5)
+
+	hits := hash.Raycast(&Vector{0, 0}, &Vector{1, 0}, 200, nil)
+	if len(hits) != 1 {
+		t.Fatalf("expected exactly one nearest hit, got %d", len(hits))
+	}
+	if hits[0].Shape != near {
+		t.Fatalf("expected the nearest circle to be hit first")
+	}
+	_ = far
+}
+
+func TestQueryRectAndQueryCircle(t *testing.T) {
+	hash := NewSpatialHash(16)
+	inside := hash.NewCircleShape(0, 0, 5)
+	outside := hash.NewCircleShape(100, 100, 5)
+
+	rectHits := hash.QueryRect(0, 0, 20, 20)
+	if len(rectHits) != 1 || rectHits[0] != inside {
+		t.Fatalf("QueryRect = %v, want just the overlapping circle", rectHits)
+	}
+
+	circHits := hash.QueryCircle(0, 0, 10)
+	if len(circHits) != 1 || circHits[0] != inside {
+		t.Fatalf("QueryCircle = %v, want just the overlapping circle", circHits)
+	}
+
+	_ = outside
+}
@@ -0,0 +1,529 @@
+package collider
+
+import "math"
+
+// cellCoordAt returns the CellCoord containing the point x,y
+func (s *SpatialHash) cellCoordAt(x, y float64) CellCoord {
+	return CellCoord{
+		int(math.Floor(x / float64(s.CellSize))),
+		int(math.Floor(y / float64(s.CellSize))),
+	}
+}
+
+// sign returns -1, 0 or 1 depending on the sign of v
+func sign(v float64) float64 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// walkCells performs a DDA traversal of the grid from (ox,oy) along (dx,dy),
+// calling visit for every cell the segment crosses, in order, along with the
+// fraction t (in [0,1]) at which that cell was entered. Traversal stops when
+// visit returns true, or once the full segment (t > 1) has been walked.
+func (s *SpatialHash) walkCells(ox, oy, dx, dy float64, visit func(cell CellCoord, tEntry float64) (stop bool)) {
+	cellSize := float64(s.CellSize)
+	cur := s.cellCoordAt(ox, oy)
+
+	if dx == 0 && dy == 0 {
+		visit(cur, 0)
+		return
+	}
+
+	stepX, stepY := sign(dx), sign(dy)
+
+	tDeltaX, tMaxX := math.Inf(1), math.Inf(1)
+	if dx != 0 {
+		tDeltaX = math.Abs(cellSize / dx)
+		var nextBoundary float64
+		if stepX > 0 {
+			nextBoundary = float64(cur.X+1) * cellSize
+		} else {
+			nextBoundary = float64(cur.X) * cellSize
+		}
+		tMaxX = (nextBoundary - ox) / dx
+	}
+
+	tDeltaY, tMaxY := math.Inf(1), math.Inf(1)
+	if dy != 0 {
+		tDeltaY = math.Abs(cellSize / dy)
+		var nextBoundary float64
+		if stepY > 0 {
+			nextBoundary = float64(cur.Y+1) * cellSize
+		} else {
+			nextBoundary = float64(cur.Y) * cellSize
+		}
+		tMaxY = (nextBoundary - oy) / dy
+	}
+
+	t := 0.0
+	if visit(cur, t) {
+		return
+	}
+
+	for t <= 1 {
+		if tMaxX < tMaxY {
+			t = tMaxX
+			cur.X += int(stepX)
+			tMaxX += tDeltaX
+		} else {
+			t = tMaxY
+			cur.Y += int(stepY)
+			tMaxY += tDeltaY
+		}
+		if t > 1 {
+			return
+		}
+		if visit(cur, t) {
+			return
+		}
+	}
+}
+
+// sweepCircleCircle returns the time of impact in [0,1] (and the collision
+// normal, pointing from other towards moving) of moving travelling by dx,dy
+// against the stationary circle other. ok is false if no impact occurs in
+// that range.
+func sweepCircleCircle(moving *CircleShape, dx, dy float64, other *CircleShape) (t float64, normal *Vector, ok bool) {
+	p := moving.Pos.Sub(other.Pos)
+	v := &Vector{dx, dy}
+	r := moving.Radius + other.Radius
+
+	c := p.X*p.X + p.Y*p.Y - r*r
+	if c < 0 {
+		// already overlapping
+		return 0, p.Normalize(), true
+	}
+
+	a := v.X*v.X + v.Y*v.Y
+	if a == 0 {
+		return 0, nil, false
+	}
+	b := 2 * (p.X*v.X + p.Y*v.Y)
+
+	disc := b*b - 4*a*c
+	if disc < 0 {
+		return 0, nil, false
+	}
+	sq := math.Sqrt(disc)
+	t0 := (-b - sq) / (2 * a)
+	if t0 < 0 || t0 > 1 {
+		return 0, nil, false
+	}
+
+	hitPos := p.Add(v.Mult(t0))
+	return t0, hitPos.Normalize(), true
+}
+
+// sweepCircleRect returns the time of impact in [0,1] of the circle moving
+// travelling by dx,dy against the stationary rectangle other, using the
+// Minkowski sum of other expanded by moving's radius (with rounded
+// corners resolved via circle-vs-point sweeps).
+func sweepCircleRect(moving *CircleShape, dx, dy float64, other *RectangleShape) (t float64, normal *Vector, ok bool) {
+	left, up, right, down := other.GetBounds()
+	r := moving.Radius
+
+	// already overlapping?
+	if col := collisionRectCirc(other, moving); col.Length() > 0 {
+		return 0, col.Mult(-1).Normalize(), true
+	}
+
+	ox, oy := moving.Pos.X, moving.Pos.Y
+
+	expLeft, expRight := left-r, right+r
+	expUp, expDown := up-r, down+r
+
+	tEnterX, tExitX := math.Inf(-1), math.Inf(1)
+	if dx != 0 {
+		tx1 := (expLeft - ox) / dx
+		tx2 := (expRight - ox) / dx
+		tEnterX, tExitX = math.Min(tx1, tx2), math.Max(tx1, tx2)
+	} else if ox < expLeft || ox > expRight {
+		return 0, nil, false
+	}
+
+	tEnterY, tExitY := math.Inf(-1), math.Inf(1)
+	if dy != 0 {
+		ty1 := (expUp - oy) / dy
+		ty2 := (expDown - oy) / dy
+		tEnterY, tExitY = math.Min(ty1, ty2), math.Max(ty1, ty2)
+	} else if oy < expUp || oy > expDown {
+		return 0, nil, false
+	}
+
+	tEnter := math.Max(tEnterX, tEnterY)
+	tExit := math.Min(tExitX, tExitY)
+	if tEnter > tExit || tEnter > 1 || tExit < 0 {
+		return 0, nil, false
+	}
+	if tEnter < 0 {
+		tEnter = 0
+	}
+
+	hitX, hitY := ox+dx*tEnter, oy+dy*tEnter
+
+	// face hit: the axis that produced tEnter determines the normal
+	faceAxisX := tEnterX >= tEnterY
+	inCorner := (hitX < left || hitX > right) && (hitY < up || hitY > down)
+
+	if !inCorner {
+		if faceAxisX {
+			if dx > 0 {
+				return tEnter, &Vector{-1, 0}, true
+			}
+			return tEnter, &Vector{1, 0}, true
+		}
+		if dy > 0 {
+			return tEnter, &Vector{0, -1}, true
+		}
+		return tEnter, &Vector{0, 1}, true
+	}
+
+	// corner case: resolve exactly against the nearest corner point
+	cornerX, cornerY := left, up
+	if hitX > right {
+		cornerX = right
+	}
+	if hitY > down {
+		cornerY = down
+	}
+	corner := &CircleShape{Pos: &Vector{cornerX, cornerY}, Radius: 0}
+	return sweepCircleCircle(moving, dx, dy, corner)
+}
+
+// sweepRectRect returns the time of impact in [0,1] of the rectangle moving
+// travelling by dx,dy against the stationary rectangle other, using the slab
+// method against the Minkowski sum of the two rectangles.
+func sweepRectRect(moving *RectangleShape, dx, dy float64, other *RectangleShape) (t float64, normal *Vector, ok bool) {
+	if col := collisionRectRect(moving, other); col.Length() > 0 {
+		return 0, col.Normalize(), true
+	}
+
+	left, up, right, down := other.GetBounds()
+	halfW, halfH := moving.Width/2, moving.Height/2
+	expLeft, expRight := left-halfW, right+halfW
+	expUp, expDown := up-halfH, down+halfH
+
+	ox, oy := moving.Pos.X, moving.Pos.Y
+
+	tEnterX, tExitX := math.Inf(-1), math.Inf(1)
+	if dx != 0 {
+		tx1 := (expLeft - ox) / dx
+		tx2 := (expRight - ox) / dx
+		tEnterX, tExitX = math.Min(tx1, tx2), math.Max(tx1, tx2)
+	} else if ox < expLeft || ox > expRight {
+		return 0, nil, false
+	}
+
+	tEnterY, tExitY := math.Inf(-1), math.Inf(1)
+	if dy != 0 {
+		ty1 := (expUp - oy) / dy
+		ty2 := (expDown - oy) / dy
+		tEnterY, tExitY = math.Min(ty1, ty2), math.Max(ty1, ty2)
+	} else if oy < expUp || oy > expDown {
+		return 0, nil, false
+	}
+
+	tEnter := math.Max(tEnterX, tEnterY)
+	tExit := math.Min(tExitX, tExitY)
+	if tEnter > tExit || tEnter > 1 || tExit < 0 {
+		return 0, nil, false
+	}
+	if tEnter < 0 {
+		tEnter = 0
+	}
+
+	if tEnterX >= tEnterY {
+		if dx > 0 {
+			return tEnter, &Vector{-1, 0}, true
+		}
+		return tEnter, &Vector{1, 0}, true
+	}
+	if dy > 0 {
+		return tEnter, &Vector{0, -1}, true
+	}
+	return tEnter, &Vector{0, 1}, true
+}
+
+// sampleCountFor returns how many evenly-spaced sub-steps bisectTOI and
+// sweepFallback should scan along a move of dx,dy, sized so consecutive
+// samples are no further apart than half of moving's smallest dimension —
+// otherwise a fast move could step clean over a thin obstacle between two
+// samples without ever testing a position that overlaps it. The count is
+// clamped to between 8 and 256, so on moves longer than 128 times that
+// dimension the samples are spaced further apart than this.
+func sampleCountFor(moving Shape, dx, dy float64) int {
+	left, up, right, down := moving.GetBounds()
+	extent := math.Min(right-left, down-up)
+	if extent <= 0 {
+		extent = 1
+	}
+	dist := math.Hypot(dx, dy)
+	samples := int(math.Ceil(dist / (extent / 2)))
+	if samples < 8 {
+		samples = 8
+	}
+	if samples > 256 {
+		samples = 256
+	}
+	return samples
+}
+
+// bisectTOI approximates the time of impact between moving and a shape it
+// has no closed-form swept solver for (polygons, segments) by scanning
+// sub-steps of the move for the first one that overlaps (checking only the
+// t=1 endpoint would miss obstacles moving passes clean through before
+// reaching it), then bisecting within the bracketed sub-step to refine it.
+// collide is called with moving already translated to the candidate t.
+// moving is restored to its starting position before returning either way.
+func bisectTOI(moving Shape, dx, dy float64, collide func() *Vector) (t float64, normal *Vector, ok bool) {
+	test := func(at float64) *Vector {
+		moving.Move(dx*at, dy*at)
+		col := collide()
+		moving.Move(-dx*at, -dy*at)
+		return col
+	}
+
+	if col := test(0); col != nil && col.Length() > 0 {
+		return 0, col.Normalize(), true
+	}
+
+	samples := sampleCountFor(moving, dx, dy)
+	lo, hi := 0.0, 1.0
+	var bestNormal *Vector
+	for i := 1; i <= samples; i++ {
+		at := float64(i) / float64(samples)
+		col := test(at)
+		if col == nil || col.Length() == 0 {
+			continue
+		}
+		lo = float64(i-1) / float64(samples)
+		hi = at
+		bestNormal = col.Normalize()
+		break
+	}
+	if bestNormal == nil {
+		return 0, nil, false
+	}
+
+	const iterations = 24
+	for i := 0; i < iterations; i++ {
+		mid := (lo + hi) / 2
+		if col := test(mid); col != nil && col.Length() > 0 {
+			hi = mid
+			bestNormal = col.Normalize()
+		} else {
+			lo = mid
+		}
+	}
+	return hi, bestNormal, true
+}
+
+// sweepShapes dispatches to the correct pairwise sweep test based on the
+// concrete types of moving and other. Polygon and segment obstacles have no
+// closed-form swept solver, so they're resolved with bisectTOI against the
+// same SAT overlap tests CheckCollisions uses, instead of being skipped.
+func sweepShapes(moving Shape, dx, dy float64, other Shape) (t float64, normal *Vector, ok bool) {
+	switch m := moving.(type) {
+	case *CircleShape:
+		switch o := other.(type) {
+		case *CircleShape:
+			return sweepCircleCircle(m, dx, dy, o)
+		case *RectangleShape:
+			return sweepCircleRect(m, dx, dy, o)
+		case *PolygonShape:
+			return bisectTOI(m, dx, dy, func() *Vector { return collisionPolyCirc(o, m).Mult(-1) })
+		case *SegmentShape:
+			return bisectTOI(m, dx, dy, func() *Vector { return collisionSegCirc(o, m).Mult(-1) })
+		}
+	case *RectangleShape:
+		switch o := other.(type) {
+		case *RectangleShape:
+			return sweepRectRect(m, dx, dy, o)
+		case *CircleShape:
+			// swap roles: sweep o backwards relative to m by negating the
+			// velocity and flipping the resulting normal
+			t, n, ok := sweepCircleRect(o, -dx, -dy, m)
+			if !ok {
+				return 0, nil, false
+			}
+			return t, n.Mult(-1), true
+		case *PolygonShape:
+			return bisectTOI(m, dx, dy, func() *Vector { return collisionPolyRect(o, m).Mult(-1) })
+		case *SegmentShape:
+			return bisectTOI(m, dx, dy, func() *Vector { return collisionSegRect(o, m).Mult(-1) })
+		}
+	}
+	return 0, nil, false
+}
+
+// walkSweptCells is walkCells widened to cover shape's own footprint: at
+// every cell the center-line DDA visits, it also visits the surrounding
+// block of cells needed to cover halfW,halfH on either side, so a shape
+// with non-negligible extent doesn't miss obstacles that only overlap its
+// edges rather than its exact center line.
+func (s *SpatialHash) walkSweptCells(ox, oy, dx, dy, halfW, halfH float64, visit func(cell CellCoord, tEntry float64) (stop bool)) {
+	cellSize := float64(s.CellSize)
+	padX := int(math.Ceil(halfW / cellSize))
+	padY := int(math.Ceil(halfH / cellSize))
+
+	visited := make(map[CellCoord]bool)
+	stop := false
+	s.walkCells(ox, oy, dx, dy, func(cell CellCoord, tEntry float64) bool {
+		for i := -padX; i <= padX && !stop; i++ {
+			for j := -padY; j <= padY && !stop; j++ {
+				c := CellCoord{cell.X + i, cell.Y + j}
+				if visited[c] {
+					continue
+				}
+				visited[c] = true
+				if visit(c, tEntry) {
+					stop = true
+				}
+			}
+		}
+		return stop
+	})
+}
+
+// Sweep walks the cells the swept AABB of shape crosses while moving by
+// dx,dy and returns the earliest time of impact t in [0,1] against any
+// candidate shape found there, along with the CollisionData for every
+// shape that shares that earliest t. If nothing is hit, t is 1 and hits
+// is empty.
+func (s *SpatialHash) Sweep(shape Shape, dx, dy float64) (float64, []CollisionData) {
+	pos := shape.GetPosition()
+	left, up, right, down := shape.GetBounds()
+	halfW, halfH := (right-left)/2, (down-up)/2
+
+	bestT := 1.0
+	hits := make([]CollisionData, 0)
+	tested := make(map[Shape]bool)
+
+	const epsilon = 1e-9
+
+	s.walkSweptCells(pos.X, pos.Y, dx, dy, halfW, halfH, func(cell CellCoord, tEntry float64) bool {
+		if tEntry > bestT+epsilon {
+			return true
+		}
+		c, ok := s.Hash[cell]
+		if !ok {
+			return false
+		}
+		for candidate := range c.Shapes {
+			if candidate == shape || tested[candidate] {
+				continue
+			}
+			tested[candidate] = true
+
+			t, normal, ok := sweepShapes(shape, dx, dy, candidate)
+			if !ok {
+				continue
+			}
+			if t < bestT-epsilon {
+				bestT = t
+				hits = hits[:0]
+				hits = append(hits, CollisionData{Other: candidate, Normal: normal})
+			} else if t < bestT+epsilon {
+				hits = append(hits, CollisionData{Other: candidate, Normal: normal})
+			}
+		}
+		return false
+	})
+
+	return bestT, hits
+}
+
+// MoveSwept moves ci by x,y up to the earliest time of impact against any
+// shape found along the way, instead of teleporting to x,y and separating
+// afterwards. It returns the time of impact t in [0,1] (1 meaning the move
+// completed without any impact) and the collisions found at that t, with
+// their Normal set so callers can build slide/bounce responses.
+func (ci *CircleShape) MoveSwept(x, y float64) (float64, []CollisionData) {
+	t, hits := ci.GetHash().Sweep(ci, x, y)
+	ci.Move(x*t, y*t)
+	return t, hits
+}
+
+// sweepFallback approximates the time of impact for shapes without a
+// closed-form swept solver (polygons, segments) by scanning sub-steps of
+// the move for the first one that overlaps anything (checking only the
+// final position would miss obstacles shape passes clean through before
+// reaching it), then bisecting within the bracketed sub-step to refine it.
+// It moves shape back to its starting position before returning; callers
+// are expected to then perform the real move themselves via
+// shape.Move(dx*t, dy*t).
+func sweepFallback(shape Shape, dx, dy float64) (float64, []CollisionData) {
+	hash := shape.GetHash()
+	if hash == nil {
+		return 1, nil
+	}
+
+	at := func(t float64) []CollisionData {
+		shape.Move(dx*t, dy*t)
+		hits := hash.CheckCollisions(shape)
+		shape.Move(-dx*t, -dy*t)
+		return hits
+	}
+	// CheckCollisions leaves Normal unset; its SeparatingVector already
+	// points away from Other, so derive the Normal from it
+	withNormals := func(hits []CollisionData) []CollisionData {
+		for i := range hits {
+			hits[i].Normal = hits[i].SeparatingVector.Normalize()
+		}
+		return hits
+	}
+
+	if hits := at(0); len(hits) > 0 {
+		return 0, withNormals(hits)
+	}
+
+	samples := sampleCountFor(shape, dx, dy)
+	lo, hi := 0.0, 1.0
+	var bestHits []CollisionData
+	for i := 1; i <= samples; i++ {
+		t := float64(i) / float64(samples)
+		hits := at(t)
+		if len(hits) == 0 {
+			continue
+		}
+		lo = float64(i-1) / float64(samples)
+		hi = t
+		bestHits = hits
+		break
+	}
+	if bestHits == nil {
+		return 1, nil
+	}
+
+	best := hi
+	const iterations = 8
+	for i := 0; i < iterations; i++ {
+		mid := (lo + hi) / 2
+		hits := at(mid)
+		if len(hits) > 0 {
+			hi = mid
+			best = mid
+			bestHits = hits
+		} else {
+			lo = mid
+		}
+	}
+	return best, withNormals(bestHits)
+}
+
+// MoveSwept moves re by x,y up to the earliest time of impact against any
+// shape found along the way, instead of teleporting to x,y and separating
+// afterwards. It returns the time of impact t in [0,1] (1 meaning the move
+// completed without any impact) and the collisions found at that t, with
+// their Normal set so callers can build slide/bounce responses.
+func (re *RectangleShape) MoveSwept(x, y float64) (float64, []CollisionData) {
+	t, hits := re.GetHash().Sweep(re, x, y)
+	re.Move(x*t, y*t)
+	return t, hits
+}
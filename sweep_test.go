@@ -0,0 +1,151 @@
+package collider
+
+import (
+	"math"
+	"testing"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestSweepCircleCircle(t *testing.T) {
+	other := &CircleShape{Pos: &Vector{100, 0}, Radius: 10}
+
+	tests := []struct {
+		name   string
+		moving *CircleShape
+		dx, dy float64
+		wantOK bool
+		wantT  float64
+	}{
+		{"hits head on", &CircleShape{Pos: &Vector{0, 0}, Radius: 10}, 100, 0, true, 0.8},
+		{"misses, passes by", &CircleShape{Pos: &Vector{0, 50}, Radius: 10}, 100, 0, false, 0},
+		{"too short to reach", &CircleShape{Pos: &Vector{0, 0}, Radius: 10}, 50, 0, false, 0},
+		{"already overlapping", &CircleShape{Pos: &Vector{95, 0}, Radius: 10}, 100, 0, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tOut, _, ok := sweepCircleCircle(tt.moving, tt.dx, tt.dy, other)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !approxEqual(tOut, tt.wantT) {
+				t.Fatalf("t = %v, want %v", tOut, tt.wantT)
+			}
+		})
+	}
+}
+
+func TestSweepCircleRect(t *testing.T) {
+	rect := &RectangleShape{Pos: &Vector{100, 0}, Width: 20, Height: 20}
+
+	tests := []struct {
+		name   string
+		moving *CircleShape
+		dx, dy float64
+		wantOK bool
+	}{
+		{"hits the face", &CircleShape{Pos: &Vector{0, 0}, Radius: 5}, 100, 0, true},
+		{"hits the rounded corner", &CircleShape{Pos: &Vector{0, -60}, Radius: 5}, 150, 80, true},
+		{"passes well clear", &CircleShape{Pos: &Vector{0, 100}, Radius: 5}, 100, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tOut, normal, ok := sweepCircleRect(tt.moving, tt.dx, tt.dy, rect)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && (tOut < 0 || tOut > 1) {
+				t.Fatalf("t = %v out of [0,1]", tOut)
+			}
+			if ok && normal == nil {
+				t.Fatalf("normal is nil on a hit")
+			}
+		})
+	}
+}
+
+func TestSweepRectRect(t *testing.T) {
+	other := &RectangleShape{Pos: &Vector{100, 0}, Width: 20, Height: 20}
+
+	tests := []struct {
+		name   string
+		moving *RectangleShape
+		dx, dy float64
+		wantOK bool
+	}{
+		{"hits head on", &RectangleShape{Pos: &Vector{0, 0}, Width: 10, Height: 10}, 100, 0, true},
+		{"passes above", &RectangleShape{Pos: &Vector{0, -100}, Width: 10, Height: 10}, 100, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, ok := sweepRectRect(tt.moving, tt.dx, tt.dy, other)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestSweepHitsOffCenterObstacle is a regression test for Sweep only
+// walking the center-line of the moving shape: the obstacle here sits
+// entirely off the rectangle's center row, inside its swept AABB, in a
+// different grid cell row than the center line ever visits.
+func TestSweepHitsOffCenterObstacle(t *testing.T) {
+	hash := NewSpatialHash(16)
+	mover := hash.NewRectangleShape(0, 50, 20, 20)
+	hash.NewRectangleShape(200, 35, 10, 10)
+
+	tImpact, hits := hash.Sweep(mover, 400, 0)
+	if tImpact >= 1 {
+		t.Fatalf("expected Sweep to report an impact, got t = %v with no hits", tImpact)
+	}
+	if len(hits) == 0 {
+		t.Fatalf("expected at least one hit, got none")
+	}
+}
+
+// TestMoveSweptAgainstPolygon is a regression test for sweepShapes falling
+// through to "no hit" on Polygon/Segment candidates: a circle moving
+// straight at a polygon obstacle must stop before passing through it.
+func TestMoveSweptAgainstPolygon(t *testing.T) {
+	hash := NewSpatialHash(16)
+	mover := hash.NewCircleShape(0, 0, 5)
+	hash.NewPolygonShape(100, 0, []*Vector{
+		{-10, -10}, {10, -10}, {10, 10}, {-10, 10},
+	})
+
+	tImpact, hits := mover.MoveSwept(200, 0)
+	if tImpact >= 1 {
+		t.Fatalf("expected MoveSwept to stop at the polygon, got t = %v", tImpact)
+	}
+	if len(hits) == 0 {
+		t.Fatalf("expected at least one hit against the polygon")
+	}
+	if mover.Pos.X >= 90 {
+		t.Fatalf("circle tunnelled through the polygon: ended up at x = %v", mover.Pos.X)
+	}
+}
+
+// TestMoveSweptAgainstSegment is the same tunnelling regression as
+// TestMoveSweptAgainstPolygon, but against a SegmentShape obstacle.
+func TestMoveSweptAgainstSegment(t *testing.T) {
+	hash := NewSpatialHash(16)
+	mover := hash.NewRectangleShape(0, 0, 10, 10)
+	hash.NewSegmentShape(100, -50, 100, 50)
+
+	tImpact, hits := mover.MoveSwept(200, 0)
+	if tImpact >= 1 {
+		t.Fatalf("expected MoveSwept to stop at the segment, got t = %v", tImpact)
+	}
+	if len(hits) == 0 {
+		t.Fatalf("expected at least one hit against the segment")
+	}
+	if mover.Pos.X >= 95 {
+		t.Fatalf("rectangle tunnelled through the segment: ended up at x = %v", mover.Pos.X)
+	}
+}
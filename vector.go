@@ -36,6 +36,11 @@ func (v *Vector) Sub(o *Vector) *Vector {
 	}
 }
 
+// Dot returns the dot product of v and o
+func (v *Vector) Dot(o *Vector) float64 {
+	return v.X*o.X + v.Y*o.Y
+}
+
 // Length returns the length of the vector
 func (v *Vector) Length() float64 {
 	return math.Sqrt(v.X*v.X + v.Y*v.Y)